@@ -0,0 +1,245 @@
+package s3stream
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+const (
+	awsMaxDeleteKeys = 1000 // https://docs.aws.amazon.com/AmazonS3/latest/API/API_DeleteObjects.html
+)
+
+// ObjectInfo describes a single object returned by List.
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	ETag         string
+	LastModified time.Time
+}
+
+// ListOptions configures a List call.
+type ListOptions struct {
+	Delimiter string
+	MaxKeys   int32
+}
+
+// List paginates ListObjectsV2 over every object under prefix in bucket and returns an
+// iterator over the results, fetching each page as iteration reaches it. Iteration stops at the first error; the caller sees it as the
+// error half of the yielded pair and should stop ranging.
+func (s Store) List(ctx context.Context, prefix, bucket string, opts ListOptions) iter.Seq2[ObjectInfo, error] {
+	input := &s3.ListObjectsV2Input{
+		Bucket:  aws.String(bucket),
+		Prefix:  aws.String(prefix),
+		MaxKeys: opts.MaxKeys,
+	}
+	// An explicit but empty Delimiter still serializes as a query param, which turns off
+	// the recursive "every object under prefix" listing every caller here relies on,
+	// so only set it when the caller actually asked for delimiter-based grouping.
+	if opts.Delimiter != "" {
+		input.Delimiter = aws.String(opts.Delimiter)
+	}
+
+	paginator := s3.NewListObjectsV2Paginator(s.api, input)
+
+	return func(yield func(ObjectInfo, error) bool) {
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				yield(ObjectInfo{}, fmt.Errorf("could not list objects under %s: %w", prefix, err))
+				return
+			}
+			for _, obj := range page.Contents {
+				info := ObjectInfo{
+					Key:  aws.ToString(obj.Key),
+					Size: obj.Size,
+					ETag: aws.ToString(obj.ETag),
+				}
+				if obj.LastModified != nil {
+					info.LastModified = *obj.LastModified
+				}
+				if !yield(info, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// DeleteError describes a single object DeleteMany failed to delete.
+type DeleteError struct {
+	Key     string
+	Code    string
+	Message string
+}
+
+func (e DeleteError) Error() string {
+	return fmt.Sprintf("could not delete %s: %s: %s", e.Key, e.Code, e.Message)
+}
+
+// DeleteMany deletes every key in keys from bucket, chunking them into batches of at most
+// 1000 keys per DeleteObjects call as required by the API. It returns a DeleteError for
+// every key S3 reports as failed; a nil, empty return means every key was deleted.
+func (s Store) DeleteMany(ctx context.Context, bucket string, keys []string) ([]DeleteError, error) {
+	var failed []DeleteError
+	for len(keys) > 0 {
+		n := awsMaxDeleteKeys
+		if n > len(keys) {
+			n = len(keys)
+		}
+		chunk := keys[:n]
+		keys = keys[n:]
+
+		ids := make([]types.ObjectIdentifier, len(chunk))
+		for i, key := range chunk {
+			ids[i] = types.ObjectIdentifier{Key: aws.String(key)}
+		}
+
+		out, err := s.api.DeleteObjects(ctx, &s3.DeleteObjectsInput{
+			Bucket: aws.String(bucket),
+			Delete: &types.Delete{Objects: ids},
+		})
+		if err != nil {
+			return failed, fmt.Errorf("could not delete objects from %s: %w", bucket, err)
+		}
+		for _, e := range out.Errors {
+			failed = append(failed, DeleteError{
+				Key:     aws.ToString(e.Key),
+				Code:    aws.ToString(e.Code),
+				Message: aws.ToString(e.Message),
+			})
+		}
+	}
+	return failed, nil
+}
+
+// Copy copies srcKey in srcBucket to dstKey in dstBucket. Objects no larger than
+// readPartSize are copied with a single CopyObject call; larger objects are copied with a
+// multipart upload driven by UploadPartCopy so the bytes never pass through the caller.
+func (s Store) Copy(ctx context.Context, srcBucket, srcKey, dstBucket, dstKey string) error {
+	objOut, err := s.api.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(srcBucket),
+		Key:    aws.String(srcKey),
+	})
+	if err != nil {
+		return fmt.Errorf("could not get metadata for object %s: %w", srcKey, err)
+	}
+
+	if objOut.ContentLength <= s.readPartSize {
+		_, err := s.api.CopyObject(ctx, &s3.CopyObjectInput{
+			Bucket:     aws.String(dstBucket),
+			Key:        aws.String(dstKey),
+			CopySource: aws.String(copySource(srcBucket, srcKey)),
+		})
+		if err != nil {
+			return fmt.Errorf("could not copy %s/%s to %s/%s: %w", srcBucket, srcKey, dstBucket, dstKey, err)
+		}
+		return nil
+	}
+
+	return s.copyMultipart(ctx, srcBucket, srcKey, dstBucket, dstKey, objOut.ContentLength)
+}
+
+func (s Store) copyMultipart(ctx context.Context, srcBucket, srcKey, dstBucket, dstKey string, length int64) error {
+	resp, err := s.api.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+		Bucket: aws.String(dstBucket),
+		Key:    aws.String(dstKey),
+	})
+	if err != nil {
+		return fmt.Errorf("could not create multipart upload for %s/%s: %w", dstBucket, dstKey, err)
+	}
+
+	source := copySource(srcBucket, srcKey)
+	var completedParts []types.CompletedPart
+	for partNumber, start := int32(1), int64(0); start < length; partNumber, start = partNumber+1, start+s.readPartSize {
+		end := start + s.readPartSize - 1
+		if end > length-1 {
+			end = length - 1
+		}
+
+		partOut, err := s.api.UploadPartCopy(ctx, &s3.UploadPartCopyInput{
+			Bucket:          aws.String(dstBucket),
+			Key:             aws.String(dstKey),
+			UploadId:        resp.UploadId,
+			PartNumber:      partNumber,
+			CopySource:      aws.String(source),
+			CopySourceRange: aws.String(fmt.Sprintf("bytes=%d-%d", start, end)),
+		})
+		if err != nil {
+			if abortErr := s.abortMultipartUpload(ctx, resp); abortErr != nil {
+				return fmt.Errorf("could not copy part %d and could not abort upload: %w; %w", partNumber, err, abortErr)
+			}
+			return fmt.Errorf("could not copy part %d of %s/%s: %w", partNumber, srcBucket, srcKey, err)
+		}
+
+		completedParts = append(completedParts, types.CompletedPart{
+			PartNumber: partNumber,
+			ETag:       partOut.CopyPartResult.ETag,
+		})
+	}
+
+	if _, err := s.completeMultipartUpload(ctx, resp, completedParts); err != nil {
+		return fmt.Errorf("could not complete copy to %s/%s: %w", dstBucket, dstKey, err)
+	}
+	return nil
+}
+
+// copySource builds the value CopyObject/UploadPartCopy expect for CopySource, which the S3
+// API requires to be URL-encoded. Each "/"-separated segment of key is escaped on its own
+// so literal path separators in the key survive; only the characters within a segment
+// (spaces, "+", "%", non-ASCII bytes, ...) get percent-encoded.
+func copySource(bucket, key string) string {
+	segments := strings.Split(key, "/")
+	for i, seg := range segments {
+		segments[i] = escapeCopySourceSegment(seg)
+	}
+	return bucket + "/" + strings.Join(segments, "/")
+}
+
+// escapeCopySourceSegment percent-encodes a single path segment. url.QueryEscape is used
+// instead of url.PathEscape because it also escapes "+", which url.PathEscape leaves as a
+// literal plus sign that S3 would otherwise decode as a space; QueryEscape's own space
+// encoding ("+") is then corrected to "%20" to keep the result valid in a URL path.
+func escapeCopySourceSegment(segment string) string {
+	return strings.ReplaceAll(url.QueryEscape(segment), "+", "%20")
+}
+
+// Prune deletes every object under prefix in bucket whose LastModified is older than
+// olderThan, built on top of List and DeleteMany.
+func (s Store) Prune(ctx context.Context, prefix, bucket string, olderThan time.Duration) ([]DeleteError, error) {
+	cutoff := time.Now().Add(-olderThan)
+
+	var (
+		keys    []string
+		failed  []DeleteError
+		listErr error
+	)
+	for obj, err := range s.List(ctx, prefix, bucket, ListOptions{}) {
+		if err != nil {
+			listErr = err
+			break
+		}
+		if obj.LastModified.Before(cutoff) {
+			keys = append(keys, obj.Key)
+		}
+	}
+
+	if len(keys) > 0 {
+		var err error
+		failed, err = s.DeleteMany(ctx, bucket, keys)
+		if err != nil {
+			return failed, err
+		}
+	}
+	if listErr != nil {
+		return failed, listErr
+	}
+	return failed, nil
+}
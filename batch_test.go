@@ -0,0 +1,287 @@
+package s3stream
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+func TestList_PaginatesAcrossMultiplePages(t *testing.T) {
+	calls := 0
+	s := newTestStore(&fakeAPI{
+		listObjectsV2Fn: func(_ context.Context, in *s3.ListObjectsV2Input) (*s3.ListObjectsV2Output, error) {
+			calls++
+			if aws.ToString(in.ContinuationToken) == "" {
+				return &s3.ListObjectsV2Output{
+					Contents:              []types.Object{{Key: aws.String("a"), Size: 1}},
+					IsTruncated:           true,
+					NextContinuationToken: aws.String("page-2"),
+				}, nil
+			}
+			if in.ContinuationToken != nil && *in.ContinuationToken == "page-2" {
+				return &s3.ListObjectsV2Output{
+					Contents:    []types.Object{{Key: aws.String("b"), Size: 2}},
+					IsTruncated: false,
+				}, nil
+			}
+			t.Fatalf("unexpected continuation token %q", aws.ToString(in.ContinuationToken))
+			return nil, nil
+		},
+	})
+
+	var keys []string
+	for obj, err := range s.List(context.Background(), "prefix/", "bucket", ListOptions{}) {
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+		keys = append(keys, obj.Key)
+	}
+	if calls != 2 {
+		t.Fatalf("expected 2 pages to be fetched, got %d", calls)
+	}
+	if len(keys) != 2 || keys[0] != "a" || keys[1] != "b" {
+		t.Fatalf("unexpected keys: %+v", keys)
+	}
+}
+
+func TestList_EmptyDelimiterIsNotSent(t *testing.T) {
+	s := newTestStore(&fakeAPI{
+		listObjectsV2Fn: func(_ context.Context, in *s3.ListObjectsV2Input) (*s3.ListObjectsV2Output, error) {
+			if in.Delimiter != nil {
+				t.Fatalf("Delimiter = %q, want nil", aws.ToString(in.Delimiter))
+			}
+			return &s3.ListObjectsV2Output{}, nil
+		},
+	})
+
+	for _, err := range s.List(context.Background(), "prefix/", "bucket", ListOptions{}) {
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+	}
+}
+
+func TestList_NonEmptyDelimiterIsSent(t *testing.T) {
+	s := newTestStore(&fakeAPI{
+		listObjectsV2Fn: func(_ context.Context, in *s3.ListObjectsV2Input) (*s3.ListObjectsV2Output, error) {
+			if aws.ToString(in.Delimiter) != "/" {
+				t.Fatalf("Delimiter = %q, want \"/\"", aws.ToString(in.Delimiter))
+			}
+			return &s3.ListObjectsV2Output{}, nil
+		},
+	})
+
+	for _, err := range s.List(context.Background(), "prefix/", "bucket", ListOptions{Delimiter: "/"}) {
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+	}
+}
+
+func TestDeleteMany_ChunksAndMergesErrors(t *testing.T) {
+	const total = 2500
+	keys := make([]string, total)
+	for i := range keys {
+		keys[i] = "key-" + string(rune('a'+i%26))
+	}
+
+	var chunkSizes []int
+	s := newTestStore(&fakeAPI{
+		deleteObjectsFn: func(_ context.Context, in *s3.DeleteObjectsInput) (*s3.DeleteObjectsOutput, error) {
+			chunkSizes = append(chunkSizes, len(in.Delete.Objects))
+			out := &s3.DeleteObjectsOutput{}
+			// Fail the first key of every chunk to verify per-key errors are merged
+			// across chunks rather than only surfacing the last one.
+			if len(in.Delete.Objects) > 0 {
+				out.Errors = []types.Error{{
+					Key:     in.Delete.Objects[0].Key,
+					Code:    aws.String("AccessDenied"),
+					Message: aws.String("denied"),
+				}}
+			}
+			return out, nil
+		},
+	})
+
+	failed, err := s.DeleteMany(context.Background(), "bucket", keys)
+	if err != nil {
+		t.Fatalf("DeleteMany() error = %v", err)
+	}
+	if len(chunkSizes) != 3 {
+		t.Fatalf("expected 3 DeleteObjects calls, got %d: %v", len(chunkSizes), chunkSizes)
+	}
+	if chunkSizes[0] != 1000 || chunkSizes[1] != 1000 || chunkSizes[2] != 500 {
+		t.Fatalf("unexpected chunk sizes: %v", chunkSizes)
+	}
+	if len(failed) != 3 {
+		t.Fatalf("expected 3 merged DeleteErrors (one per chunk), got %d: %+v", len(failed), failed)
+	}
+}
+
+func TestDeleteMany_StopsOnError(t *testing.T) {
+	boom := errors.New("boom")
+	calls := 0
+	s := newTestStore(&fakeAPI{
+		deleteObjectsFn: func(context.Context, *s3.DeleteObjectsInput) (*s3.DeleteObjectsOutput, error) {
+			calls++
+			return nil, boom
+		},
+	})
+
+	keys := make([]string, 1500)
+	for i := range keys {
+		keys[i] = "key"
+	}
+
+	_, err := s.DeleteMany(context.Background(), "bucket", keys)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if calls != 1 {
+		t.Fatalf("expected DeleteObjects to be called exactly once after the first failure, got %d calls", calls)
+	}
+}
+
+func TestCopy_SmallObjectUsesSingleCopyObject(t *testing.T) {
+	var gotInput *s3.CopyObjectInput
+	uploadPartCopyCalled := false
+	s := newTestStore(&fakeAPI{
+		headObjectFn: func(context.Context, *s3.HeadObjectInput) (*s3.HeadObjectOutput, error) {
+			return &s3.HeadObjectOutput{ContentLength: 5}, nil
+		},
+		copyObjectFn: func(_ context.Context, in *s3.CopyObjectInput) (*s3.CopyObjectOutput, error) {
+			gotInput = in
+			return &s3.CopyObjectOutput{}, nil
+		},
+		uploadPartCopyFn: func(context.Context, *s3.UploadPartCopyInput) (*s3.UploadPartCopyOutput, error) {
+			uploadPartCopyCalled = true
+			return nil, errors.New("should not be called for a small object")
+		},
+	})
+	s.readPartSize = 10
+
+	if err := s.Copy(context.Background(), "src-bucket", "my photos/a.jpg", "dst-bucket", "dst-key"); err != nil {
+		t.Fatalf("Copy() error = %v", err)
+	}
+	if uploadPartCopyCalled {
+		t.Fatal("UploadPartCopy should not be called for an object smaller than readPartSize")
+	}
+	if aws.ToString(gotInput.Bucket) != "dst-bucket" || aws.ToString(gotInput.Key) != "dst-key" {
+		t.Errorf("unexpected CopyObjectInput: %+v", gotInput)
+	}
+	if want := "src-bucket/my%20photos/a.jpg"; aws.ToString(gotInput.CopySource) != want {
+		t.Errorf("CopySource = %q, want %q", aws.ToString(gotInput.CopySource), want)
+	}
+}
+
+func TestCopy_LargeObjectUsesMultipart(t *testing.T) {
+	var partRanges []string
+	var completedParts []types.CompletedPart
+	s := newTestStore(&fakeAPI{
+		headObjectFn: func(context.Context, *s3.HeadObjectInput) (*s3.HeadObjectOutput, error) {
+			return &s3.HeadObjectOutput{ContentLength: 25}, nil
+		},
+		createMultipartUploadFn: func(context.Context, *s3.CreateMultipartUploadInput) (*s3.CreateMultipartUploadOutput, error) {
+			return testResp(), nil
+		},
+		uploadPartCopyFn: func(_ context.Context, in *s3.UploadPartCopyInput) (*s3.UploadPartCopyOutput, error) {
+			partRanges = append(partRanges, aws.ToString(in.CopySourceRange))
+			return &s3.UploadPartCopyOutput{
+				CopyPartResult: &types.CopyPartResult{ETag: aws.String("etag-" + aws.ToString(in.CopySourceRange))},
+			}, nil
+		},
+		completeMultipartUploadFn: func(_ context.Context, in *s3.CompleteMultipartUploadInput) (*s3.CompleteMultipartUploadOutput, error) {
+			completedParts = in.MultipartUpload.Parts
+			return &s3.CompleteMultipartUploadOutput{}, nil
+		},
+	})
+	s.readPartSize = 10
+
+	if err := s.Copy(context.Background(), "src-bucket", "src-key", "dst-bucket", "dst-key"); err != nil {
+		t.Fatalf("Copy() error = %v", err)
+	}
+	if want := []string{"bytes=0-9", "bytes=10-19", "bytes=20-24"}; !equalStrings(partRanges, want) {
+		t.Fatalf("partRanges = %v, want %v", partRanges, want)
+	}
+	if len(completedParts) != 3 || completedParts[0].PartNumber != 1 || completedParts[2].PartNumber != 3 {
+		t.Fatalf("unexpected completed parts: %+v", completedParts)
+	}
+}
+
+func TestCopyMultipart_AbortsOnPartError(t *testing.T) {
+	aborted := false
+	boom := errors.New("boom")
+	s := newTestStore(&fakeAPI{
+		createMultipartUploadFn: func(context.Context, *s3.CreateMultipartUploadInput) (*s3.CreateMultipartUploadOutput, error) {
+			return testResp(), nil
+		},
+		uploadPartCopyFn: func(context.Context, *s3.UploadPartCopyInput) (*s3.UploadPartCopyOutput, error) {
+			return nil, boom
+		},
+		abortMultipartUploadFn: func(context.Context, *s3.AbortMultipartUploadInput) (*s3.AbortMultipartUploadOutput, error) {
+			aborted = true
+			return &s3.AbortMultipartUploadOutput{}, nil
+		},
+	})
+	s.readPartSize = 10
+
+	err := s.copyMultipart(context.Background(), "src-bucket", "src-key", "dst-bucket", "dst-key", 25)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if !aborted {
+		t.Fatal("expected the multipart upload to be aborted after a part copy failure")
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestPrune_DeletesOnlyObjectsOlderThanCutoff(t *testing.T) {
+	now := time.Now()
+	old := now.Add(-48 * time.Hour)
+	recent := now.Add(-1 * time.Hour)
+
+	var deleted []string
+	s := newTestStore(&fakeAPI{
+		listObjectsV2Fn: func(context.Context, *s3.ListObjectsV2Input) (*s3.ListObjectsV2Output, error) {
+			return &s3.ListObjectsV2Output{
+				Contents: []types.Object{
+					{Key: aws.String("old"), LastModified: aws.Time(old)},
+					{Key: aws.String("recent"), LastModified: aws.Time(recent)},
+				},
+			}, nil
+		},
+		deleteObjectsFn: func(_ context.Context, in *s3.DeleteObjectsInput) (*s3.DeleteObjectsOutput, error) {
+			for _, obj := range in.Delete.Objects {
+				deleted = append(deleted, aws.ToString(obj.Key))
+			}
+			return &s3.DeleteObjectsOutput{}, nil
+		},
+	})
+
+	failed, err := s.Prune(context.Background(), "prefix/", "bucket", 24*time.Hour)
+	if err != nil {
+		t.Fatalf("Prune() error = %v", err)
+	}
+	if len(failed) != 0 {
+		t.Fatalf("unexpected delete errors: %+v", failed)
+	}
+	if len(deleted) != 1 || deleted[0] != "old" {
+		t.Fatalf("deleted = %v, want only [old]", deleted)
+	}
+}
@@ -0,0 +1,82 @@
+package s3stream
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// fakeAPI is a minimal s3API implementation for unit tests. Each test sets only the
+// function fields it needs; calling an unset one panics via a nil pointer dereference,
+// which reads clearly as "this test exercised a call path it didn't expect to."
+type fakeAPI struct {
+	createMultipartUploadFn   func(context.Context, *s3.CreateMultipartUploadInput) (*s3.CreateMultipartUploadOutput, error)
+	uploadPartFn              func(context.Context, *s3.UploadPartInput) (*s3.UploadPartOutput, error)
+	abortMultipartUploadFn    func(context.Context, *s3.AbortMultipartUploadInput) (*s3.AbortMultipartUploadOutput, error)
+	completeMultipartUploadFn func(context.Context, *s3.CompleteMultipartUploadInput) (*s3.CompleteMultipartUploadOutput, error)
+	listPartsFn               func(context.Context, *s3.ListPartsInput) (*s3.ListPartsOutput, error)
+	headObjectFn              func(context.Context, *s3.HeadObjectInput) (*s3.HeadObjectOutput, error)
+	getObjectFn               func(context.Context, *s3.GetObjectInput) (*s3.GetObjectOutput, error)
+	deleteObjectsFn           func(context.Context, *s3.DeleteObjectsInput) (*s3.DeleteObjectsOutput, error)
+	copyObjectFn              func(context.Context, *s3.CopyObjectInput) (*s3.CopyObjectOutput, error)
+	uploadPartCopyFn          func(context.Context, *s3.UploadPartCopyInput) (*s3.UploadPartCopyOutput, error)
+	listObjectsV2Fn           func(context.Context, *s3.ListObjectsV2Input) (*s3.ListObjectsV2Output, error)
+}
+
+func (f *fakeAPI) CreateMultipartUpload(ctx context.Context, params *s3.CreateMultipartUploadInput, _ ...func(*s3.Options)) (*s3.CreateMultipartUploadOutput, error) {
+	return f.createMultipartUploadFn(ctx, params)
+}
+
+func (f *fakeAPI) UploadPart(ctx context.Context, params *s3.UploadPartInput, _ ...func(*s3.Options)) (*s3.UploadPartOutput, error) {
+	return f.uploadPartFn(ctx, params)
+}
+
+func (f *fakeAPI) AbortMultipartUpload(ctx context.Context, params *s3.AbortMultipartUploadInput, _ ...func(*s3.Options)) (*s3.AbortMultipartUploadOutput, error) {
+	return f.abortMultipartUploadFn(ctx, params)
+}
+
+func (f *fakeAPI) CompleteMultipartUpload(ctx context.Context, params *s3.CompleteMultipartUploadInput, _ ...func(*s3.Options)) (*s3.CompleteMultipartUploadOutput, error) {
+	return f.completeMultipartUploadFn(ctx, params)
+}
+
+func (f *fakeAPI) ListParts(ctx context.Context, params *s3.ListPartsInput, _ ...func(*s3.Options)) (*s3.ListPartsOutput, error) {
+	return f.listPartsFn(ctx, params)
+}
+
+func (f *fakeAPI) HeadObject(ctx context.Context, params *s3.HeadObjectInput, _ ...func(*s3.Options)) (*s3.HeadObjectOutput, error) {
+	return f.headObjectFn(ctx, params)
+}
+
+func (f *fakeAPI) GetObject(ctx context.Context, params *s3.GetObjectInput, _ ...func(*s3.Options)) (*s3.GetObjectOutput, error) {
+	return f.getObjectFn(ctx, params)
+}
+
+func (f *fakeAPI) DeleteObjects(ctx context.Context, params *s3.DeleteObjectsInput, _ ...func(*s3.Options)) (*s3.DeleteObjectsOutput, error) {
+	return f.deleteObjectsFn(ctx, params)
+}
+
+func (f *fakeAPI) CopyObject(ctx context.Context, params *s3.CopyObjectInput, _ ...func(*s3.Options)) (*s3.CopyObjectOutput, error) {
+	return f.copyObjectFn(ctx, params)
+}
+
+func (f *fakeAPI) UploadPartCopy(ctx context.Context, params *s3.UploadPartCopyInput, _ ...func(*s3.Options)) (*s3.UploadPartCopyOutput, error) {
+	return f.uploadPartCopyFn(ctx, params)
+}
+
+func (f *fakeAPI) ListObjectsV2(ctx context.Context, params *s3.ListObjectsV2Input, _ ...func(*s3.Options)) (*s3.ListObjectsV2Output, error) {
+	return f.listObjectsV2Fn(ctx, params)
+}
+
+// newTestStore builds a Store backed by api, with sane defaults a test can override by
+// mutating the returned value's exported-via-same-package fields.
+func newTestStore(api s3API) Store {
+	return Store{
+		api:               api,
+		readPartSize:      readBlockSize,
+		uploadConcurrency: defaultUploadConcurrency,
+		maxBufferedParts:  defaultMaxBufferedParts,
+		getConcurrency:    defaultGetConcurrency,
+		presignerCache:    &lazyPresigner{},
+		progressStore:     NewMemoryProgressStore(),
+	}
+}
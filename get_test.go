@@ -0,0 +1,141 @@
+package s3stream
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// rangeObjectFn returns a getObjectFn that serves byte ranges out of content, parsing the
+// "bytes=start-end" Range header the way S3 itself would.
+func rangeObjectFn(t *testing.T, content []byte) func(context.Context, *s3.GetObjectInput) (*s3.GetObjectOutput, error) {
+	t.Helper()
+	return func(_ context.Context, in *s3.GetObjectInput) (*s3.GetObjectOutput, error) {
+		var start, end int64
+		if _, err := fmt.Sscanf(aws.ToString(in.Range), "bytes=%d-%d", &start, &end); err != nil {
+			t.Fatalf("could not parse range %q: %v", aws.ToString(in.Range), err)
+		}
+		if end > int64(len(content))-1 {
+			end = int64(len(content)) - 1
+		}
+		return &s3.GetObjectOutput{Body: io.NopCloser(bytes.NewReader(content[start : end+1]))}, nil
+	}
+}
+
+func TestGetSequential_Success(t *testing.T) {
+	content := []byte("0123456789abcdefghij") // 20 bytes
+	s := newTestStore(&fakeAPI{getObjectFn: rangeObjectFn(t, content)})
+	s.readPartSize = 7 // uneven part size forces a remainder range
+
+	pr, pw := io.Pipe()
+	go s.getSequential(context.Background(), pw, "", "bucket", "key", int64(len(content)))
+
+	got, err := io.ReadAll(pr)
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if !bytes.Equal(got, content) {
+		t.Fatalf("got %q, want %q", got, content)
+	}
+}
+
+func TestGetSequential_ErrorClosesPipeAndStops(t *testing.T) {
+	boom := errors.New("boom")
+	calls := 0
+	s := newTestStore(&fakeAPI{
+		getObjectFn: func(context.Context, *s3.GetObjectInput) (*s3.GetObjectOutput, error) {
+			calls++
+			return nil, boom
+		},
+	})
+	s.readPartSize = 5
+
+	pr, pw := io.Pipe()
+	go s.getSequential(context.Background(), pw, "", "bucket", "key", 20)
+
+	_, err := io.ReadAll(pr)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if calls != 1 {
+		t.Fatalf("expected getDataInRange to be called exactly once after the first failure, got %d calls", calls)
+	}
+}
+
+func TestGetConcurrent_ReassemblesInOffsetOrder(t *testing.T) {
+	content := []byte("the quick brown fox jumps over the lazy dog!!!") // 46 bytes
+	s := newTestStore(&fakeAPI{
+		getObjectFn: func(ctx context.Context, in *s3.GetObjectInput) (*s3.GetObjectOutput, error) {
+			// Make the first range the slowest to complete, so the coordinator must
+			// wait for it rather than writing later ranges out of order.
+			if aws.ToString(in.Range) == "bytes=0-9" {
+				time.Sleep(20 * time.Millisecond)
+			}
+			return rangeObjectFn(t, content)(ctx, in)
+		},
+	})
+	s.readPartSize = 10
+	s.getConcurrency = 4
+
+	pr, pw := io.Pipe()
+	go s.getConcurrent(context.Background(), pw, "", "bucket", "key", int64(len(content)))
+
+	got, err := io.ReadAll(pr)
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if !bytes.Equal(got, content) {
+		t.Fatalf("got %q, want %q", got, content)
+	}
+}
+
+func TestGetConcurrent_ErrorCancelsOutstandingRanges(t *testing.T) {
+	length := int64(40)
+	started := make(chan struct{})
+	cancelled := make(chan struct{}, 4)
+	s := newTestStore(&fakeAPI{
+		getObjectFn: func(ctx context.Context, in *s3.GetObjectInput) (*s3.GetObjectOutput, error) {
+			switch aws.ToString(in.Range) {
+			case "bytes=0-9":
+				// Wait until the range this test cancels is actually in flight, so the
+				// failure here can't race ahead and cancel before there's anything to
+				// observe the cancellation.
+				<-started
+				return nil, errors.New("boom")
+			case "bytes=10-19":
+				close(started)
+				<-ctx.Done()
+				select {
+				case cancelled <- struct{}{}:
+				default:
+				}
+				return nil, ctx.Err()
+			default:
+				return &s3.GetObjectOutput{Body: io.NopCloser(bytes.NewReader(make([]byte, 10)))}, nil
+			}
+		},
+	})
+	s.readPartSize = 10
+	s.getConcurrency = 4
+
+	pr, pw := io.Pipe()
+	go s.getConcurrent(context.Background(), pw, "", "bucket", "key", length)
+
+	_, err := io.ReadAll(pr)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	select {
+	case <-cancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the range 10-19 fetch to observe context cancellation after range 0-9 failed")
+	}
+}
@@ -0,0 +1,116 @@
+package s3stream
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/md5"  // nolint: gosec
+	"crypto/sha1" // nolint: gosec
+	"crypto/sha256"
+	"encoding/base64"
+	"io"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+func TestPut_WithHashersSetsChecksumHeadersAndResult(t *testing.T) {
+	var gotInput *s3.UploadPartInput
+	s := newTestStore(&fakeAPI{
+		createMultipartUploadFn: func(context.Context, *s3.CreateMultipartUploadInput) (*s3.CreateMultipartUploadOutput, error) {
+			return testResp(), nil
+		},
+		uploadPartFn: func(_ context.Context, in *s3.UploadPartInput) (*s3.UploadPartOutput, error) {
+			gotInput = in
+			return &s3.UploadPartOutput{ETag: aws.String("etag-1")}, nil
+		},
+		completeMultipartUploadFn: func(context.Context, *s3.CompleteMultipartUploadInput) (*s3.CompleteMultipartUploadOutput, error) {
+			return &s3.CompleteMultipartUploadOutput{ETag: aws.String("final-etag")}, nil
+		},
+	})
+	s.uploadConcurrency = 1
+	s.hashers = []crypto.Hash{crypto.MD5, crypto.SHA1, crypto.SHA256}
+
+	content := []byte("checksum this content please")
+	result, err := s.Put(context.Background(), "", "bucket", "key", bytes.NewReader(content))
+	if err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	if gotInput == nil {
+		t.Fatal("UploadPart was never called")
+	}
+
+	md5Sum := md5.Sum(content)   // nolint: gosec
+	sha1Sum := sha1.Sum(content) // nolint: gosec
+	sha256Sum := sha256.Sum256(content)
+
+	if got := aws.ToString(gotInput.ContentMD5); got != base64.StdEncoding.EncodeToString(md5Sum[:]) {
+		t.Errorf("ContentMD5 = %q, want %q", got, base64.StdEncoding.EncodeToString(md5Sum[:]))
+	}
+	if got := aws.ToString(gotInput.ChecksumSHA1); got != base64.StdEncoding.EncodeToString(sha1Sum[:]) {
+		t.Errorf("ChecksumSHA1 = %q, want %q", got, base64.StdEncoding.EncodeToString(sha1Sum[:]))
+	}
+	if got := aws.ToString(gotInput.ChecksumSHA256); got != base64.StdEncoding.EncodeToString(sha256Sum[:]) {
+		t.Errorf("ChecksumSHA256 = %q, want %q", got, base64.StdEncoding.EncodeToString(sha256Sum[:]))
+	}
+
+	if !bytes.Equal(result.Hashes[crypto.MD5], md5Sum[:]) {
+		t.Errorf("Hashes[MD5] = %x, want %x", result.Hashes[crypto.MD5], md5Sum)
+	}
+	if !bytes.Equal(result.Hashes[crypto.SHA1], sha1Sum[:]) {
+		t.Errorf("Hashes[SHA1] = %x, want %x", result.Hashes[crypto.SHA1], sha1Sum)
+	}
+	if !bytes.Equal(result.Hashes[crypto.SHA256], sha256Sum[:]) {
+		t.Errorf("Hashes[SHA256] = %x, want %x", result.Hashes[crypto.SHA256], sha256Sum)
+	}
+}
+
+func TestGet_WithHashersHashesNilUntilEOF(t *testing.T) {
+	content := []byte("hello world, checksum me please")
+	s := newTestStore(&fakeAPI{
+		headObjectFn: func(context.Context, *s3.HeadObjectInput) (*s3.HeadObjectOutput, error) {
+			return &s3.HeadObjectOutput{ContentLength: int64(len(content))}, nil
+		},
+		getObjectFn: rangeObjectFn(t, content),
+	})
+	s.getConcurrency = 1
+	s.hashers = []crypto.Hash{crypto.SHA256}
+
+	rc, err := s.Get(context.Background(), "", "bucket", "key")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	hrc, ok := rc.(*HashingReadCloser)
+	if !ok {
+		t.Fatalf("Get() returned %T, want *HashingReadCloser", rc)
+	}
+
+	partial := make([]byte, 5)
+	n, err := hrc.Read(partial)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if hrc.Hashes() != nil {
+		t.Fatal("Hashes() should be nil before EOF")
+	}
+
+	rest, err := io.ReadAll(hrc)
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	got := append(partial[:n], rest...)
+	if !bytes.Equal(got, content) {
+		t.Fatalf("got %q, want %q", got, content)
+	}
+
+	sums := hrc.Hashes()
+	if sums == nil {
+		t.Fatal("Hashes() should be non-nil after EOF")
+	}
+	want := sha256.Sum256(content)
+	if !bytes.Equal(sums[crypto.SHA256], want[:]) {
+		t.Errorf("Hashes()[SHA256] = %x, want %x", sums[crypto.SHA256], want)
+	}
+}
@@ -0,0 +1,137 @@
+package s3stream
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// fakePresigner is a minimal Presigner implementation for unit tests, following the same
+// one-function-field-per-method pattern as fakeAPI.
+type fakePresigner struct {
+	presignGetObjectFn  func(context.Context, *s3.GetObjectInput) (*v4.PresignedHTTPRequest, error)
+	presignPutObjectFn  func(context.Context, *s3.PutObjectInput) (*v4.PresignedHTTPRequest, error)
+	presignUploadPartFn func(context.Context, *s3.UploadPartInput) (*v4.PresignedHTTPRequest, error)
+}
+
+func (f *fakePresigner) PresignGetObject(ctx context.Context, params *s3.GetObjectInput, _ ...func(*s3.PresignOptions)) (*v4.PresignedHTTPRequest, error) {
+	return f.presignGetObjectFn(ctx, params)
+}
+
+func (f *fakePresigner) PresignPutObject(ctx context.Context, params *s3.PutObjectInput, _ ...func(*s3.PresignOptions)) (*v4.PresignedHTTPRequest, error) {
+	return f.presignPutObjectFn(ctx, params)
+}
+
+func (f *fakePresigner) PresignUploadPart(ctx context.Context, params *s3.UploadPartInput, _ ...func(*s3.PresignOptions)) (*v4.PresignedHTTPRequest, error) {
+	return f.presignUploadPartFn(ctx, params)
+}
+
+func TestPresignGet_BuildsExpectedInput(t *testing.T) {
+	var gotInput *s3.GetObjectInput
+	s := newTestStore(nil)
+	if err := WithPresignClient(&fakePresigner{
+		presignGetObjectFn: func(_ context.Context, in *s3.GetObjectInput) (*v4.PresignedHTTPRequest, error) {
+			gotInput = in
+			return &v4.PresignedHTTPRequest{URL: "https://example.com/get"}, nil
+		},
+	})(&s); err != nil {
+		t.Fatalf("WithPresignClient() error = %v", err)
+	}
+
+	url, _, err := s.PresignGet(context.Background(), "prefix/", "bucket", "key", time.Minute)
+	if err != nil {
+		t.Fatalf("PresignGet() error = %v", err)
+	}
+	if url != "https://example.com/get" {
+		t.Errorf("url = %q, want the presigned URL", url)
+	}
+	if gotInput.Bucket == nil || *gotInput.Bucket != "bucket" || gotInput.Key == nil || *gotInput.Key != "prefix/key" {
+		t.Errorf("unexpected GetObjectInput: %+v", gotInput)
+	}
+}
+
+func TestPresignPut_BuildsExpectedInput(t *testing.T) {
+	var gotInput *s3.PutObjectInput
+	s := newTestStore(nil)
+	if err := WithPresignClient(&fakePresigner{
+		presignPutObjectFn: func(_ context.Context, in *s3.PutObjectInput) (*v4.PresignedHTTPRequest, error) {
+			gotInput = in
+			return &v4.PresignedHTTPRequest{URL: "https://example.com/put"}, nil
+		},
+	})(&s); err != nil {
+		t.Fatalf("WithPresignClient() error = %v", err)
+	}
+
+	url, _, err := s.PresignPut(context.Background(), "prefix/", "bucket", "key", time.Minute)
+	if err != nil {
+		t.Fatalf("PresignPut() error = %v", err)
+	}
+	if url != "https://example.com/put" {
+		t.Errorf("url = %q, want the presigned URL", url)
+	}
+	if gotInput.Bucket == nil || *gotInput.Bucket != "bucket" || gotInput.Key == nil || *gotInput.Key != "prefix/key" {
+		t.Errorf("unexpected PutObjectInput: %+v", gotInput)
+	}
+}
+
+func TestPresignMultipart_BuildsExpectedInputAndPartURLs(t *testing.T) {
+	var gotPartInput *s3.UploadPartInput
+	s := newTestStore(&fakeAPI{
+		createMultipartUploadFn: func(context.Context, *s3.CreateMultipartUploadInput) (*s3.CreateMultipartUploadOutput, error) {
+			return testResp(), nil
+		},
+	})
+	if err := WithPresignClient(&fakePresigner{
+		presignUploadPartFn: func(_ context.Context, in *s3.UploadPartInput) (*v4.PresignedHTTPRequest, error) {
+			gotPartInput = in
+			return &v4.PresignedHTTPRequest{URL: "https://example.com/part"}, nil
+		},
+	})(&s); err != nil {
+		t.Fatalf("WithPresignClient() error = %v", err)
+	}
+
+	uploadID, partURL, completeURL, err := s.PresignMultipart(context.Background(), "", "bucket", "key", time.Minute)
+	if err != nil {
+		t.Fatalf("PresignMultipart() error = %v", err)
+	}
+	if uploadID != "upload-id" {
+		t.Errorf("uploadID = %q, want %q", uploadID, "upload-id")
+	}
+	if completeURL != "" {
+		t.Errorf("completeURL = %q, want empty", completeURL)
+	}
+
+	url, err := partURL(3)
+	if err != nil {
+		t.Fatalf("partURL() error = %v", err)
+	}
+	if url != "https://example.com/part" {
+		t.Errorf("url = %q, want the presigned URL", url)
+	}
+	if gotPartInput.PartNumber != 3 || gotPartInput.UploadId == nil || *gotPartInput.UploadId != "upload-id" {
+		t.Errorf("unexpected UploadPartInput: %+v", gotPartInput)
+	}
+}
+
+// TestPresignClient_CachesDefaultPresigner asserts presignClient only builds the default
+// s3.PresignClient once and shares it across calls, even across copies of Store, the bug
+// fixed alongside this test.
+func TestPresignClient_CachesDefaultPresigner(t *testing.T) {
+	s := newTestStore(nil)
+	s.rawClient = &s3.Client{}
+
+	first := s.presignClient()
+	second := s.presignClient()
+	if first != second {
+		t.Fatal("presignClient() should return the same cached instance on every call")
+	}
+
+	copied := s
+	third := copied.presignClient()
+	if first != third {
+		t.Fatal("presignClient() should share its cache across copies of Store")
+	}
+}
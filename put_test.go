@@ -0,0 +1,143 @@
+package s3stream
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+func testResp() *s3.CreateMultipartUploadOutput {
+	return &s3.CreateMultipartUploadOutput{
+		Bucket:   aws.String("bucket"),
+		Key:      aws.String("key"),
+		UploadId: aws.String("upload-id"),
+	}
+}
+
+func TestUploadPartsSequential_Success(t *testing.T) {
+	s := newTestStore(&fakeAPI{
+		uploadPartFn: func(_ context.Context, in *s3.UploadPartInput) (*s3.UploadPartOutput, error) {
+			return &s3.UploadPartOutput{ETag: aws.String("etag-" + strconv.Itoa(int(in.PartNumber)))}, nil
+		},
+	})
+
+	var confirmed []types.CompletedPart
+	content := []byte("hello resumable world")
+	total, err := s.uploadPartsSequential(context.Background(), testResp(), bytes.NewReader(content), 3, func(p types.CompletedPart) error {
+		confirmed = append(confirmed, p)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("uploadPartsSequential() error = %v", err)
+	}
+	if total != len(content) {
+		t.Fatalf("total = %d, want %d", total, len(content))
+	}
+	if len(confirmed) != 1 || confirmed[0].PartNumber != 3 || aws.ToString(confirmed[0].ETag) != "etag-3" {
+		t.Fatalf("unexpected confirmed parts: %+v", confirmed)
+	}
+}
+
+func TestUploadPartsSequential_UploadErrorStopsEarly(t *testing.T) {
+	boom := errors.New("boom")
+	s := newTestStore(&fakeAPI{
+		uploadPartFn: func(context.Context, *s3.UploadPartInput) (*s3.UploadPartOutput, error) {
+			return nil, boom
+		},
+	})
+
+	onPartCalls := 0
+	_, err := s.uploadPartsSequential(context.Background(), testResp(), bytes.NewReader([]byte("some data")), 1, func(types.CompletedPart) error {
+		onPartCalls++
+		return nil
+	})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if onPartCalls != 0 {
+		t.Fatalf("onPart should not be called when the upload fails, got %d calls", onPartCalls)
+	}
+}
+
+func TestUploadPartsConcurrent_Success(t *testing.T) {
+	s := newTestStore(&fakeAPI{
+		uploadPartFn: func(_ context.Context, in *s3.UploadPartInput) (*s3.UploadPartOutput, error) {
+			return &s3.UploadPartOutput{ETag: aws.String("etag-" + strconv.Itoa(int(in.PartNumber)))}, nil
+		},
+	})
+	s.uploadConcurrency = 2
+
+	content := []byte("a small single-part payload")
+	var confirmed []types.CompletedPart
+	total, err := s.uploadPartsConcurrent(context.Background(), testResp(), bytes.NewReader(content), 1, func(p types.CompletedPart) error {
+		confirmed = append(confirmed, p)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("uploadPartsConcurrent() error = %v", err)
+	}
+	if total != len(content) {
+		t.Fatalf("total = %d, want %d", total, len(content))
+	}
+	if len(confirmed) != 1 || confirmed[0].PartNumber != 1 {
+		t.Fatalf("unexpected confirmed parts: %+v", confirmed)
+	}
+}
+
+// TestUploadPartsConcurrent_WorkerErrorCancelsOthers drives two parts through two workers:
+// one blocks on its context while the other fails immediately. It asserts the failure
+// cancels the shared context so the blocked worker observes it, instead of leaking a
+// goroutine stuck talking to S3 forever.
+func TestUploadPartsConcurrent_WorkerErrorCancelsOthers(t *testing.T) {
+	var startOnce sync.Once
+	started := make(chan struct{})
+	cancelled := make(chan struct{}, maxUploadRetries)
+	s := newTestStore(&fakeAPI{
+		uploadPartFn: func(ctx context.Context, in *s3.UploadPartInput) (*s3.UploadPartOutput, error) {
+			switch in.PartNumber {
+			case 1:
+				// uploadPart retries every failure up to maxUploadRetries, so this closes
+				// started instead of sending once: a plain send would only unblock part 2's
+				// first attempt and deadlock its retries on an already-drained channel.
+				startOnce.Do(func() { close(started) })
+				<-ctx.Done()
+				select {
+				case cancelled <- struct{}{}:
+				default:
+				}
+				return nil, ctx.Err()
+			case 2:
+				// Wait until part 1 is actually in flight, so this failure can't race
+				// ahead and cancel before there's anything to observe the cancellation.
+				<-started
+				return nil, errors.New("boom")
+			default:
+				t.Errorf("unexpected part number %d", in.PartNumber)
+				return nil, errors.New("unexpected part")
+			}
+		},
+	})
+	s.uploadConcurrency = 2
+
+	data := make([]byte, writeBlockSize*2) // exactly two full parts, no remainder
+	_, err := s.uploadPartsConcurrent(context.Background(), testResp(), bytes.NewReader(data), 1, func(types.CompletedPart) error {
+		return nil
+	})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	select {
+	case <-cancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected part 1's upload to observe context cancellation after part 2 failed")
+	}
+}
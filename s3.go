@@ -3,29 +3,94 @@ package s3stream
 import (
 	"bytes"
 	"context"
+	"crypto"
+	_ "crypto/md5"    // register crypto.MD5
+	_ "crypto/sha1"   // register crypto.SHA1
+	_ "crypto/sha256" // register crypto.SHA256
+	_ "crypto/sha512" // register crypto.SHA512
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"hash"
 	"io"
+	"net/http"
+	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/aws/smithy-go"
+	"golang.org/x/sync/errgroup"
 )
 
 const (
-	readBlockSize    = 16 * 1024 * 1024       // 16 MB
-	writeBlockSize   = 8 * 1024 * 1024        // 8 MB
-	tempBlockSize    = 1 * 1024 * 1024        // 1 MB
-	awsMaxParts      = 10000                  // https://docs.aws.amazon.com/AmazonS3/latest/dev/qfacts.html
-	awsMaxPartSize   = 5 * 1024 * 1024 * 1024 // 5 GB
-	maxUploadRetries = 5
+	readBlockSize            = 16 * 1024 * 1024       // 16 MB
+	writeBlockSize           = 8 * 1024 * 1024        // 8 MB
+	tempBlockSize            = 1 * 1024 * 1024        // 1 MB
+	awsMaxParts              = 10000                  // https://docs.aws.amazon.com/AmazonS3/latest/dev/qfacts.html
+	awsMaxPartSize           = 5 * 1024 * 1024 * 1024 // 5 GB
+	maxUploadRetries         = 5
+	defaultUploadConcurrency = 5  // number of workers uploading parts in parallel
+	defaultMaxBufferedParts  = 20 // depth of the channel between the reader and the upload workers
+	defaultGetConcurrency    = 5  // number of workers downloading ranges in parallel
 )
 
+// partBufferPool recycles the writeBlockSize buffers used to stage parts read from the
+// source reader, so a concurrent Put does not allocate one buffer per part.
+var partBufferPool = sync.Pool{
+	New: func() interface{} {
+		return make([]byte, writeBlockSize)
+	},
+}
+
+// s3API is the subset of *s3.Client's methods Store depends on. *s3.Client satisfies it
+// implicitly; it exists so unit tests can substitute a fake client instead of talking to
+// real S3.
+type s3API interface {
+	CreateMultipartUpload(ctx context.Context, params *s3.CreateMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.CreateMultipartUploadOutput, error)
+	UploadPart(ctx context.Context, params *s3.UploadPartInput, optFns ...func(*s3.Options)) (*s3.UploadPartOutput, error)
+	AbortMultipartUpload(ctx context.Context, params *s3.AbortMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.AbortMultipartUploadOutput, error)
+	CompleteMultipartUpload(ctx context.Context, params *s3.CompleteMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.CompleteMultipartUploadOutput, error)
+	ListParts(ctx context.Context, params *s3.ListPartsInput, optFns ...func(*s3.Options)) (*s3.ListPartsOutput, error)
+	HeadObject(ctx context.Context, params *s3.HeadObjectInput, optFns ...func(*s3.Options)) (*s3.HeadObjectOutput, error)
+	GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error)
+	DeleteObjects(ctx context.Context, params *s3.DeleteObjectsInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectsOutput, error)
+	CopyObject(ctx context.Context, params *s3.CopyObjectInput, optFns ...func(*s3.Options)) (*s3.CopyObjectOutput, error)
+	UploadPartCopy(ctx context.Context, params *s3.UploadPartCopyInput, optFns ...func(*s3.Options)) (*s3.UploadPartCopyOutput, error)
+	ListObjectsV2(ctx context.Context, params *s3.ListObjectsV2Input, optFns ...func(*s3.Options)) (*s3.ListObjectsV2Output, error)
+}
+
 // Store is the S3 implementation of the Store interface.
 type Store struct {
-	api          *s3.Client
-	readPartSize int64
+	api               s3API
+	rawClient         *s3.Client // only needed to build the default presign client; nil in tests using a fake api
+	readPartSize      int64
+	uploadConcurrency int
+	maxBufferedParts  int
+	getConcurrency    int
+	hashers           []crypto.Hash
+	presigner         Presigner
+	presignerCache    *lazyPresigner
+	progressStore     ProgressStore
+}
+
+// lazyPresigner holds the default s3.PresignClient built on first use. It is referenced
+// through a pointer so every copy of a value-receiver Store shares the same cached client
+// instead of each call site rebuilding one.
+type lazyPresigner struct {
+	once   sync.Once
+	client Presigner
+}
+
+// Presigner generates presigned HTTP requests for S3 operations. *s3.PresignClient
+// satisfies this interface; it is narrowed down to the operations Store presigns so tests
+// can substitute a mock via WithPresignClient.
+type Presigner interface {
+	PresignGetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.PresignOptions)) (*v4.PresignedHTTPRequest, error)
+	PresignPutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.PresignOptions)) (*v4.PresignedHTTPRequest, error)
+	PresignUploadPart(ctx context.Context, params *s3.UploadPartInput, optFns ...func(*s3.PresignOptions)) (*v4.PresignedHTTPRequest, error)
 }
 
 // NewStore returns a Store given the input options.
@@ -37,8 +102,14 @@ func NewStore(conf aws.Config, opts ...func(*Store) error) (Store, error) {
 // NewStoreWithClient returns a Store given the input client.
 func NewStoreWithClient(client *s3.Client, opts ...func(*Store) error) (Store, error) {
 	s := Store{
-		api:          client,
-		readPartSize: readBlockSize,
+		api:               client,
+		rawClient:         client,
+		readPartSize:      readBlockSize,
+		uploadConcurrency: defaultUploadConcurrency,
+		maxBufferedParts:  defaultMaxBufferedParts,
+		getConcurrency:    defaultGetConcurrency,
+		presignerCache:    &lazyPresigner{},
+		progressStore:     NewMemoryProgressStore(),
 	}
 	for _, o := range opts {
 		err := o(&s)
@@ -62,7 +133,219 @@ func WithReadPartSize(size int64) func(s *Store) error {
 	}
 }
 
+// WithUploadConcurrency sets the number of workers that call UploadPart in parallel
+// during Put. A value of 1 disables the producer/consumer pipeline and falls back to
+// the original sequential read-then-upload behaviour.
+func WithUploadConcurrency(n int) func(s *Store) error {
+	return func(s *Store) error {
+		if n < 1 {
+			return errors.New("upload concurrency must be at least 1")
+		}
+		s.uploadConcurrency = n
+		return nil
+	}
+}
+
+// WithMaxBufferedParts sets the depth of the channel used to hand parts read from the
+// source reader off to the upload workers during Put, bounding how far the reader can
+// run ahead of the slowest worker.
+func WithMaxBufferedParts(n int) func(s *Store) error {
+	return func(s *Store) error {
+		if n < 1 {
+			return errors.New("max buffered parts must be at least 1")
+		}
+		s.maxBufferedParts = n
+		return nil
+	}
+}
+
+// WithGetConcurrency sets the number of workers that fetch ranges in parallel during Get.
+// A value of 1 disables the worker pool and falls back to the original single-stream
+// range-by-range download.
+func WithGetConcurrency(n int) func(s *Store) error {
+	return func(s *Store) error {
+		if n < 1 {
+			return errors.New("get concurrency must be at least 1")
+		}
+		s.getConcurrency = n
+		return nil
+	}
+}
+
+// WithHashers enables streaming checksum computation on Put and Get. The requested
+// algorithms are hashed in a single pass as bytes flow through Put/Get, and Put additionally
+// sets the matching S3 integrity header (ContentMD5, ChecksumSHA1, ChecksumSHA256) on each
+// UploadPart call. Supported algorithms are crypto.MD5, crypto.SHA1, crypto.SHA256 and
+// crypto.SHA512.
+func WithHashers(algs ...crypto.Hash) func(s *Store) error {
+	return func(s *Store) error {
+		for _, alg := range algs {
+			switch alg {
+			case crypto.MD5, crypto.SHA1, crypto.SHA256, crypto.SHA512:
+			default:
+				return fmt.Errorf("unsupported hash algorithm: %v", alg)
+			}
+		}
+		s.hashers = algs
+		return nil
+	}
+}
+
+// newHashers returns a fresh hash.Hash for every algorithm configured via WithHashers.
+func (s Store) newHashers() map[crypto.Hash]hash.Hash {
+	if len(s.hashers) == 0 {
+		return nil
+	}
+	hashers := make(map[crypto.Hash]hash.Hash, len(s.hashers))
+	for _, alg := range s.hashers {
+		hashers[alg] = alg.New()
+	}
+	return hashers
+}
+
+// HashingReadCloser wraps the io.ReadCloser returned by Get, computing the checksums
+// requested via WithHashers as bytes are read. Hashes returns nil until the wrapped reader
+// has been read to EOF.
+type HashingReadCloser struct {
+	io.ReadCloser
+	hashers map[crypto.Hash]hash.Hash
+	mu      sync.Mutex
+	done    bool
+}
+
+// Read implements io.Reader, feeding every byte read into the configured hashers.
+func (h *HashingReadCloser) Read(p []byte) (int, error) {
+	n, err := h.ReadCloser.Read(p)
+	if n > 0 {
+		for _, hh := range h.hashers {
+			hh.Write(p[:n]) // nolint: errcheck, gosec
+		}
+	}
+	if err == io.EOF {
+		h.mu.Lock()
+		h.done = true
+		h.mu.Unlock()
+	}
+	return n, err
+}
+
+// Hashes returns the computed checksums, keyed by algorithm. It returns nil until the
+// wrapped reader has been fully read.
+func (h *HashingReadCloser) Hashes() map[crypto.Hash][]byte {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !h.done {
+		return nil
+	}
+	sums := make(map[crypto.Hash][]byte, len(h.hashers))
+	for alg, hh := range h.hashers {
+		sums[alg] = hh.Sum(nil)
+	}
+	return sums
+}
+
+// WithPresignClient overrides the Presigner used by PresignGet, PresignPut and
+// PresignMultipart. Without this option, Store builds one from its *s3.Client lazily, the
+// first time it is needed. This exists mainly to substitute a mock in tests.
+func WithPresignClient(p Presigner) func(s *Store) error {
+	return func(s *Store) error {
+		if p == nil {
+			return errors.New("presign client cannot be nil")
+		}
+		s.presigner = p
+		return nil
+	}
+}
+
+// presignClient returns the configured Presigner, constructing and caching the default
+// s3.PresignClient on first use if none was set via WithPresignClient.
+func (s Store) presignClient() Presigner {
+	if s.presigner != nil {
+		return s.presigner
+	}
+	s.presignerCache.once.Do(func() {
+		s.presignerCache.client = s3.NewPresignClient(s.rawClient)
+	})
+	return s.presignerCache.client
+}
+
+// PresignGet returns a presigned URL for downloading the object directly from S3, along
+// with the headers that must be sent with the request, valid for ttl.
+func (s Store) PresignGet(ctx context.Context, prefix, bucketname, name string, ttl time.Duration) (string, http.Header, error) {
+	req, err := s.presignClient().PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucketname),
+		Key:    aws.String(prefix + name),
+	}, s3.WithPresignExpires(ttl))
+	if err != nil {
+		return "", nil, fmt.Errorf("could not presign get for object %s: %w", name, err)
+	}
+	return req.URL, req.SignedHeader, nil
+}
+
+// PresignPut returns a presigned URL for uploading the object directly to S3 in a single
+// PUT, along with the headers that must be sent with the request, valid for ttl.
+func (s Store) PresignPut(ctx context.Context, prefix, bucketname, name string, ttl time.Duration) (string, http.Header, error) {
+	req, err := s.presignClient().PresignPutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(bucketname),
+		Key:    aws.String(prefix + name),
+	}, s3.WithPresignExpires(ttl))
+	if err != nil {
+		return "", nil, fmt.Errorf("could not presign put for object %s: %w", name, err)
+	}
+	return req.URL, req.SignedHeader, nil
+}
+
+// PresignMultipart starts a multipart upload and returns its UploadId together with
+// partURLs, a function that presigns an UploadPart URL for a given part number (valid for
+// ttl) so a browser can PUT chunks directly to S3. completeURL is currently always empty:
+// the installed AWS SDK for Go v2 does not provide presigning for CompleteMultipartUpload,
+// since that request's signature would need to cover the final parts-list body, which isn't
+// known until every part has been uploaded. Callers must complete the upload through a
+// trusted backend using UploadId once all parts are in.
+func (s Store) PresignMultipart(ctx context.Context, prefix, bucketname, name string, ttl time.Duration) (string, func(partNumber int) (string, error), string, error) {
+	resp, err := s.api.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+		Bucket: aws.String(bucketname),
+		Key:    aws.String(prefix + name),
+	})
+	if err != nil {
+		return "", nil, "", fmt.Errorf("could not create multipart upload: %w.", err)
+	}
+
+	partURLs := func(partNumber int) (string, error) {
+		req, err := s.presignClient().PresignUploadPart(ctx, &s3.UploadPartInput{
+			Bucket:     resp.Bucket,
+			Key:        resp.Key,
+			UploadId:   resp.UploadId,
+			PartNumber: int32(partNumber),
+		}, s3.WithPresignExpires(ttl))
+		if err != nil {
+			return "", fmt.Errorf("could not presign part %d: %w", partNumber, err)
+		}
+		return req.URL, nil
+	}
+
+	return aws.ToString(resp.UploadId), partURLs, "", nil
+}
+
+// WithProgressStore overrides where StartUpload persists and looks up resumable upload
+// state. It defaults to an in-memory store, which cannot survive the process restarting;
+// use NewFileProgressStore to resume uploads across runs.
+func WithProgressStore(ps ProgressStore) func(s *Store) error {
+	return func(s *Store) error {
+		if ps == nil {
+			return errors.New("progress store cannot be nil")
+		}
+		s.progressStore = ps
+		return nil
+	}
+}
+
 // Get returns the content of the file in input reading it from the underlying S3 bucket.
+// When the Store's get concurrency is greater than 1 (the default), ranges are fetched by a
+// pool of workers in parallel and reassembled into the returned reader in offset order,
+// instead of being downloaded one range at a time on a single goroutine. When WithHashers
+// was used to configure the Store, the returned io.ReadCloser is a *HashingReadCloser whose
+// Hashes method exposes the computed checksums once EOF is reached.
 func (s Store) Get(ctx context.Context, prefix, bucketname, filename string) (io.ReadCloser, error) {
 	objOut, err := s.api.HeadObject(ctx, &s3.HeadObjectInput{
 		Bucket: aws.String(bucketname),
@@ -74,43 +357,151 @@ func (s Store) Get(ctx context.Context, prefix, bucketname, filename string) (io
 
 	length := objOut.ContentLength
 	pr, pw := io.Pipe()
-	go func() {
-		defer pw.Close() // nolint: errcheck, gosec
+	if s.getConcurrency <= 1 {
+		go s.getSequential(ctx, pw, prefix, bucketname, filename, length)
+	} else {
+		go s.getConcurrent(ctx, pw, prefix, bucketname, filename, length)
+	}
 
-		var (
-			i, start, remainder int64
-			rangeSpecifier      string
-		)
+	hashers := s.newHashers()
+	if len(hashers) == 0 {
+		return pr, nil
+	}
+	return &HashingReadCloser{ReadCloser: pr, hashers: hashers}, nil
+}
 
-		for i = 0; i < length/s.readPartSize; i++ {
-			start = i * s.readPartSize
-			rangeSpecifier = fmt.Sprintf("bytes=%d-%d", start, start+s.readPartSize-1)
-			data, err := s.getDataInRange(ctx, prefix, bucketname, filename, rangeSpecifier)
-			if err != nil {
-				pw.CloseWithError(fmt.Errorf("could not get data: %w", err)) // nolint: errcheck, gosec
-			}
-			_, err = pw.Write(data)
-			if err != nil {
-				pw.CloseWithError(fmt.Errorf("could not write data: %w", err)) // nolint: errcheck, gosec
-			}
+// getSequential fetches the object range-by-range on a single goroutine, preserving the
+// original single-stream download behaviour.
+func (s Store) getSequential(ctx context.Context, pw *io.PipeWriter, prefix, bucketname, filename string, length int64) {
+	defer pw.Close() // nolint: errcheck, gosec
 
+	var (
+		i, start, remainder int64
+		rangeSpecifier      string
+	)
+
+	for i = 0; i < length/s.readPartSize; i++ {
+		start = i * s.readPartSize
+		rangeSpecifier = fmt.Sprintf("bytes=%d-%d", start, start+s.readPartSize-1)
+		data, err := s.getDataInRange(ctx, prefix, bucketname, filename, rangeSpecifier)
+		if err != nil {
+			pw.CloseWithError(fmt.Errorf("could not get data: %w", err)) // nolint: errcheck, gosec
+			return
 		}
-		remainder = length % s.readPartSize
-		if remainder > 0 {
-			start = (length / s.readPartSize) * s.readPartSize
-			rangeSpecifier = fmt.Sprintf("bytes=%d-%d", start, start+remainder-1)
-			data, err := s.getDataInRange(ctx, prefix, bucketname, filename, rangeSpecifier)
-			if err != nil {
-				pw.CloseWithError(fmt.Errorf("could not get data: %w", err)) // nolint: errcheck, gosec
+		_, err = pw.Write(data)
+		if err != nil {
+			pw.CloseWithError(fmt.Errorf("could not write data: %w", err)) // nolint: errcheck, gosec
+			return
+		}
+	}
+	remainder = length % s.readPartSize
+	if remainder > 0 {
+		start = (length / s.readPartSize) * s.readPartSize
+		rangeSpecifier = fmt.Sprintf("bytes=%d-%d", start, start+remainder-1)
+		data, err := s.getDataInRange(ctx, prefix, bucketname, filename, rangeSpecifier)
+		if err != nil {
+			pw.CloseWithError(fmt.Errorf("could not get data: %w", err)) // nolint: errcheck, gosec
+			return
+		}
+		_, err = pw.Write(data)
+		if err != nil {
+			pw.CloseWithError(fmt.Errorf("could not get data: %w", err)) // nolint: errcheck, gosec
+			return
+		}
+	}
+}
+
+// rangeResult is what a get worker hands back to the reassembly coordinator for one range.
+type rangeResult struct {
+	buf []byte
+	n   int
+	err error
+}
+
+// getConcurrent dispatches ceil(length/readPartSize) range requests to a pool of
+// s.getConcurrency workers, each reading its range into a buffer taken from a sync.Pool.
+// Workers signal completion into a dedicated ready slot per range; the coordinator below
+// advances the write cursor in strict offset order, flushing each buffer into pw and
+// releasing it back to the pool. Any range error cancels the shared context and closes pw
+// with that error.
+func (s Store) getConcurrent(ctx context.Context, pw *io.PipeWriter, prefix, bucketname, filename string, length int64) {
+	defer pw.Close() // nolint: errcheck, gosec
+
+	npart := int((length + s.readPartSize - 1) / s.readPartSize)
+	if npart == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	bufPool := sync.Pool{
+		New: func() interface{} {
+			return make([]byte, s.readPartSize)
+		},
+	}
+
+	jobs := make(chan int)
+	// One buffered slot per range, not per worker: reusing a worker-sized ring here would
+	// alias two different ranges onto the same slot whenever npart > s.getConcurrency, and
+	// the coordinator below has no way to tell which range a given slot's result is for.
+	ready := make([]chan rangeResult, npart)
+	for i := range ready {
+		ready[i] = make(chan rangeResult, 1)
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < s.getConcurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range jobs {
+				start := int64(idx) * s.readPartSize
+				end := start + s.readPartSize - 1
+				if end > length-1 {
+					end = length - 1
+				}
+				rangeSpecifier := fmt.Sprintf("bytes=%d-%d", start, end)
+				buf, _ := bufPool.Get().([]byte)
+				n, err := s.readDataInRange(ctx, prefix, bucketname, filename, rangeSpecifier, buf[:end-start+1])
+				select {
+				case ready[idx] <- rangeResult{buf: buf, n: n, err: err}:
+				case <-ctx.Done():
+				}
 			}
-			_, err = pw.Write(data)
-			if err != nil {
-				pw.CloseWithError(fmt.Errorf("could not get data: %w", err)) // nolint: errcheck, gosec
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for idx := 0; idx < npart; idx++ {
+			select {
+			case jobs <- idx:
+			case <-ctx.Done():
+				return
 			}
 		}
 	}()
 
-	return pr, nil
+	for idx := 0; idx < npart; idx++ {
+		res := <-ready[idx]
+		if res.err != nil {
+			cancel()
+			pw.CloseWithError(fmt.Errorf("could not get data: %w", res.err)) // nolint: errcheck, gosec
+			bufPool.Put(res.buf)                                             // nolint: staticcheck
+			wg.Wait()
+			return
+		}
+		_, err := pw.Write(res.buf[:res.n])
+		bufPool.Put(res.buf) // nolint: staticcheck
+		if err != nil {
+			cancel()
+			pw.CloseWithError(fmt.Errorf("could not write data: %w", err)) // nolint: errcheck, gosec
+			wg.Wait()
+			return
+		}
+	}
+	wg.Wait()
 }
 
 func (s Store) getDataInRange(ctx context.Context, prefix, bucketname, filename, rangeSpecifier string) ([]byte, error) {
@@ -130,40 +521,107 @@ func (s Store) getDataInRange(ctx context.Context, prefix, bucketname, filename,
 	return data, nil
 }
 
-// Put stores the content of the reader in input with the specified name.
-// Returns number of bytes written and an error if any.
-func (s Store) Put(ctx context.Context, prefix, bucketname, filename string, r io.Reader) (int, error) {
-	// initialize upload
-	input := &s3.CreateMultipartUploadInput{
+// readDataInRange fetches rangeSpecifier and reads it into the caller-provided buffer,
+// returning the number of bytes read. buf must be sized to exactly the range length, as used
+// by getConcurrent's pooled buffers.
+func (s Store) readDataInRange(ctx context.Context, prefix, bucketname, filename, rangeSpecifier string, buf []byte) (int, error) {
+	resp, err := s.api.GetObject(ctx, &s3.GetObjectInput{
 		Bucket: aws.String(bucketname),
 		Key:    aws.String(prefix + filename),
+		Range:  aws.String(rangeSpecifier),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("could not get object %s: %w", rangeSpecifier, err)
 	}
-	resp, err := s.api.CreateMultipartUpload(ctx, input)
+	defer resp.Body.Close() // nolint: errcheck, gosec
+	n, err := io.ReadFull(resp.Body, buf)
 	if err != nil {
-		return 0, fmt.Errorf("could not create multipart upload: %w.", err)
+		return n, fmt.Errorf("could not read from resp.Body: %w", err)
 	}
+	return n, nil
+}
 
-	var (
-		reachedEOF     bool
-		completedParts []types.CompletedPart
-	)
+// PutResult is returned by Put once the object has been fully uploaded.
+type PutResult struct {
+	Bytes     int
+	Hashes    map[crypto.Hash][]byte
+	ETag      string
+	VersionID string
+}
+
+// Put stores the content of the reader in input with the specified name.
+// It is a convenience wrapper around StartUpload: it drives an ephemeral, non-resumable
+// Upload handle to completion and aborts it on any error. When the Store's upload
+// concurrency is greater than 1 (the default), a single producer goroutine reads parts from
+// r while a pool of workers call UploadPart in parallel, which keeps network round trips to
+// S3 from stalling the reader. When WithHashers was used to configure the Store, the
+// requested checksums are computed in a single pass as bytes flow to S3 and are returned in
+// PutResult.Hashes.
+func (s Store) Put(ctx context.Context, prefix, bucketname, filename string, r io.Reader) (PutResult, error) {
+	// A throwaway ProgressStore keeps Put from resuming, or leaving state behind in, the
+	// Store's own ProgressStore.
+	ephemeral := s
+	ephemeral.progressStore = NewMemoryProgressStore()
+	u, err := ephemeral.StartUpload(ctx, prefix, bucketname, filename)
+	if err != nil {
+		return PutResult{}, err
+	}
+
+	hashers := s.newHashers()
+	if len(hashers) > 0 {
+		writers := make([]io.Writer, 0, len(hashers))
+		for _, h := range hashers {
+			writers = append(writers, h)
+		}
+		r = io.TeeReader(r, io.MultiWriter(writers...))
+	}
+
+	total, err := u.WriteFrom(r)
+	if err != nil {
+		uplderr := fmt.Errorf("could not upload part: %w", err)
+		if aberr := u.Abort(); aberr != nil {
+			return PutResult{Bytes: total}, fmt.Errorf("could not abort upload: %w", uplderr)
+		}
+		return PutResult{Bytes: total}, fmt.Errorf("upload aborted: %w", uplderr)
+	}
+
+	completeResp, err := u.Complete()
+	if err != nil {
+		return PutResult{Bytes: total}, fmt.Errorf("error while completing upload: %w", err)
+	}
+
+	result := PutResult{
+		Bytes:     total,
+		ETag:      aws.ToString(completeResp.ETag),
+		VersionID: aws.ToString(completeResp.VersionId),
+	}
+	if len(hashers) > 0 {
+		result.Hashes = make(map[crypto.Hash][]byte, len(hashers))
+		for alg, h := range hashers {
+			result.Hashes[alg] = h.Sum(nil)
+		}
+	}
+	return result, nil
+}
+
+// uploadPartsSequential reads into a writeBlockSize buffer and uploads each part before
+// resuming the read, preserving the original single-part-in-flight semantics. Parts are
+// numbered starting at startPart, and onPart is invoked after each one is confirmed by S3.
+// It does not abort or complete the multipart upload; that is the caller's responsibility.
+func (s Store) uploadPartsSequential(ctx context.Context, resp *s3.CreateMultipartUploadOutput, r io.Reader, startPart int, onPart func(types.CompletedPart) error) (int, error) {
+	var reachedEOF bool
 
 	// buffering up to writeBlockSize MB and then uploading the block
 	dataidx, total := 0, 0
 	temp := make([]byte, tempBlockSize)
 	data := make([]byte, writeBlockSize)
-	i := 1
+	i := startPart
 	for i <= awsMaxParts {
 		// read into temporary buffer
 		n, err := r.Read(temp)
 		if err != nil {
 			if err != io.EOF {
-				readerr := fmt.Errorf("could not read part %d: %w", i, err)
-				aberr := s.abortMultipartUpload(ctx, resp)
-				if aberr != nil {
-					return total, fmt.Errorf("could not abort upload: %w", readerr)
-				}
-				return total, readerr
+				return total, fmt.Errorf("could not read part %d: %w", i, err)
 			}
 			reachedEOF = true
 		}
@@ -172,14 +630,11 @@ func (s Store) Put(ctx context.Context, prefix, bucketname, filename string, r i
 		if dataidx+n > writeBlockSize {
 			completedPart, err := s.uploadPart(ctx, resp, data[:dataidx], i)
 			if err != nil {
-				uplderr := fmt.Errorf("could not upload part %d: %w", i, err)
-				aberr := s.abortMultipartUpload(ctx, resp)
-				if aberr != nil {
-					return total, fmt.Errorf("could not abort upload: %w", uplderr)
-				}
-				return total, fmt.Errorf("upload aborted: %w", uplderr)
+				return total, fmt.Errorf("could not upload part %d: %w", i, err)
+			}
+			if err := onPart(*completedPart); err != nil {
+				return total, fmt.Errorf("could not persist progress for part %d: %w", i, err)
 			}
-			completedParts = append(completedParts, *completedPart)
 			i++
 			dataidx = 0
 		}
@@ -191,39 +646,138 @@ func (s Store) Put(ctx context.Context, prefix, bucketname, filename string, r i
 
 		// upload remaining content
 		if reachedEOF {
-			completedPart, err := s.uploadPart(ctx, resp, data[:dataidx], i)
-			if err != nil {
-				uplderr := fmt.Errorf("could not upload part %d: %w", i, err)
-				aberr := s.abortMultipartUpload(ctx, resp)
-				if aberr != nil {
-					return total, fmt.Errorf("could not abort upload: %w", uplderr)
+			if dataidx > 0 {
+				completedPart, err := s.uploadPart(ctx, resp, data[:dataidx], i)
+				if err != nil {
+					return total, fmt.Errorf("could not upload part %d: %w", i, err)
+				}
+				if err := onPart(*completedPart); err != nil {
+					return total, fmt.Errorf("could not persist progress for part %d: %w", i, err)
 				}
-				return total, fmt.Errorf("upload aborted: %w", uplderr)
 			}
-			completedParts = append(completedParts, *completedPart)
-			break
+			return total, nil
 		}
 	}
 
-	// check for which reason it got out of the loop
-	if i > awsMaxParts && !reachedEOF {
-		maxparterr := fmt.Errorf("could not upload whole content... MaxPartsNumber limit reached. Aborting: %w...", err)
-		aberr := s.abortMultipartUpload(ctx, resp)
-		if aberr != nil {
-			return total, fmt.Errorf("could not abort upload: %w", maxparterr)
-		}
-		return total, maxparterr
+	return total, fmt.Errorf("could not upload whole content... MaxPartsNumber limit reached. Aborting")
+}
+
+// partUpload is a unit of work handed from the producer goroutine to an upload worker:
+// buf[:size] holds the bytes read for partNumber, and buf was taken from partBufferPool.
+type partUpload struct {
+	partNumber int
+	buf        []byte
+	size       int
+}
+
+// uploadPartsConcurrent runs a producer/consumer pipeline: a single goroutine reads parts
+// from r into buffers taken from partBufferPool and sends them on a bounded channel, while
+// s.uploadConcurrency workers call UploadPart in parallel. Parts are numbered starting at
+// startPart, and onPart is invoked - possibly from multiple goroutines at once - after each
+// part is confirmed by S3, so it must synchronize its own state. On any error the shared
+// context is cancelled and outstanding buffers are drained back to the pool; it does not
+// abort or complete the multipart upload, that is the caller's responsibility.
+func (s Store) uploadPartsConcurrent(ctx context.Context, resp *s3.CreateMultipartUploadOutput, r io.Reader, startPart int, onPart func(types.CompletedPart) error) (int, error) {
+	gctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	work := make(chan partUpload, s.maxBufferedParts)
+	g, gctx := errgroup.WithContext(gctx)
+
+	var (
+		mu    sync.Mutex
+		total int
+	)
+
+	g.Go(func() error {
+		return s.produceParts(gctx, work, r, startPart)
+	})
+
+	for i := 0; i < s.uploadConcurrency; i++ {
+		g.Go(func() error {
+			for pu := range work {
+				completedPart, err := s.uploadPart(gctx, resp, pu.buf[:pu.size], pu.partNumber)
+				size := pu.size
+				partBufferPool.Put(pu.buf) // nolint: staticcheck
+				if err != nil {
+					return fmt.Errorf("could not upload part %d: %w", pu.partNumber, err)
+				}
+				if err := onPart(*completedPart); err != nil {
+					return fmt.Errorf("could not persist progress for part %d: %w", pu.partNumber, err)
+				}
+				mu.Lock()
+				total += size
+				mu.Unlock()
+			}
+			return nil
+		})
 	}
 
-	// finalize upload
-	_, err = s.completeMultipartUpload(ctx, resp, completedParts)
-	if err != nil {
-		return total, fmt.Errorf("error while completing upload: %w", err)
+	if err := g.Wait(); err != nil {
+		for pu := range work {
+			partBufferPool.Put(pu.buf) // nolint: staticcheck
+		}
+		return total, fmt.Errorf("could not upload part: %w", err)
 	}
 
 	return total, nil
 }
 
+// produceParts reads r into writeBlockSize buffers taken from partBufferPool and sends
+// them on work in part order starting at startPart, closing work when it returns so the
+// upload workers always drain to completion. It stops early if ctx is cancelled by a
+// failing worker.
+func (s Store) produceParts(ctx context.Context, work chan<- partUpload, r io.Reader, startPart int) error {
+	defer close(work)
+
+	temp := make([]byte, tempBlockSize)
+	buf, _ := partBufferPool.Get().([]byte)
+	dataidx := 0
+	partNumber := startPart
+
+	send := func(size int) error {
+		select {
+		case work <- partUpload{partNumber: partNumber, buf: buf, size: size}:
+			return nil
+		case <-ctx.Done():
+			partBufferPool.Put(buf) // nolint: staticcheck
+			return ctx.Err()
+		}
+	}
+
+	for {
+		if partNumber > awsMaxParts {
+			partBufferPool.Put(buf) // nolint: staticcheck
+			return fmt.Errorf("could not upload whole content... MaxPartsNumber limit reached. Aborting")
+		}
+
+		n, err := r.Read(temp)
+		if n > 0 {
+			if dataidx+n > len(buf) {
+				if sendErr := send(dataidx); sendErr != nil {
+					return sendErr
+				}
+				partNumber++
+				buf, _ = partBufferPool.Get().([]byte)
+				dataidx = 0
+			}
+			copy(buf[dataidx:], temp[:n])
+			dataidx += n
+		}
+		if err != nil {
+			if err != io.EOF {
+				partBufferPool.Put(buf) // nolint: staticcheck
+				return fmt.Errorf("could not read part %d: %w", partNumber, err)
+			}
+			if dataidx > 0 {
+				return send(dataidx)
+			}
+			partBufferPool.Put(buf) // nolint: staticcheck
+			return nil
+		}
+	}
+}
+
 // uploadPart uploads a single part and returns a CompletedPart object and an error if any.
 // It will retry five times if not able to upload.
 func (s Store) uploadPart(ctx context.Context, resp *s3.CreateMultipartUploadOutput, data []byte, partNumber int) (*types.CompletedPart, error) {
@@ -237,6 +791,7 @@ func (s Store) uploadPart(ctx context.Context, resp *s3.CreateMultipartUploadOut
 		UploadId:      resp.UploadId,
 		ContentLength: int64(len(data)),
 	}
+	s.setPartChecksums(partInput, data)
 
 	for tryNum := 1; tryNum <= maxUploadRetries; tryNum++ {
 		var err error
@@ -262,6 +817,28 @@ func (s Store) uploadPart(ctx context.Context, resp *s3.CreateMultipartUploadOut
 	}, nil
 }
 
+// setPartChecksums computes the part-local digests for whichever algorithms were requested
+// via WithHashers and sets the matching S3 integrity header on partInput, so S3 verifies the
+// part server-side instead of relying solely on the final ETag.
+func (s Store) setPartChecksums(partInput *s3.UploadPartInput, data []byte) {
+	for _, alg := range s.hashers {
+		switch alg {
+		case crypto.MD5:
+			sum := crypto.MD5.New()
+			sum.Write(data) // nolint: errcheck, gosec
+			partInput.ContentMD5 = aws.String(base64.StdEncoding.EncodeToString(sum.Sum(nil)))
+		case crypto.SHA1:
+			sum := crypto.SHA1.New()
+			sum.Write(data) // nolint: errcheck, gosec
+			partInput.ChecksumSHA1 = aws.String(base64.StdEncoding.EncodeToString(sum.Sum(nil)))
+		case crypto.SHA256:
+			sum := crypto.SHA256.New()
+			sum.Write(data) // nolint: errcheck, gosec
+			partInput.ChecksumSHA256 = aws.String(base64.StdEncoding.EncodeToString(sum.Sum(nil)))
+		}
+	}
+}
+
 // abortMultipartUpload aborts the multipart upload process
 func (s Store) abortMultipartUpload(ctx context.Context, resp *s3.CreateMultipartUploadOutput) error {
 	abortInput := &s3.AbortMultipartUploadInput{
@@ -270,7 +847,10 @@ func (s Store) abortMultipartUpload(ctx context.Context, resp *s3.CreateMultipar
 		UploadId: resp.UploadId,
 	}
 	_, err := s.api.AbortMultipartUpload(ctx, abortInput)
-	return fmt.Errorf("could not abort upload: %w", err)
+	if err != nil {
+		return fmt.Errorf("could not abort upload: %w", err)
+	}
+	return nil
 }
 
 // completeMultipartUpload completes the multipart upload process
@@ -0,0 +1,273 @@
+package s3stream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// ErrProgressNotFound is returned by a ProgressStore's Load when no state has been saved
+// for the given key.
+var ErrProgressNotFound = errors.New("upload progress not found")
+
+// UploadState is the persisted state of an in-progress multipart upload, enough to resume
+// it: which UploadId it belongs to and which parts have already been confirmed by S3.
+type UploadState struct {
+	UploadId string
+	Parts    []types.CompletedPart
+}
+
+// ProgressStore persists UploadState so an interrupted Upload can be resumed by StartUpload
+// instead of starting over from byte zero.
+type ProgressStore interface {
+	Save(key string, state UploadState) error
+	Load(key string) (UploadState, error)
+}
+
+// MemoryProgressStore keeps upload progress in memory. It is the default ProgressStore and
+// is only useful for resuming within the same process; progress is lost on restart.
+type MemoryProgressStore struct {
+	mu     sync.Mutex
+	states map[string]UploadState
+}
+
+// NewMemoryProgressStore returns an empty MemoryProgressStore.
+func NewMemoryProgressStore() *MemoryProgressStore {
+	return &MemoryProgressStore{states: make(map[string]UploadState)}
+}
+
+// Save records state for key, overwriting any previously saved state.
+func (m *MemoryProgressStore) Save(key string, state UploadState) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.states[key] = state
+	return nil
+}
+
+// Load returns the state previously saved for key, or ErrProgressNotFound if there is none.
+func (m *MemoryProgressStore) Load(key string) (UploadState, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	state, ok := m.states[key]
+	if !ok {
+		return UploadState{}, ErrProgressNotFound
+	}
+	return state, nil
+}
+
+// FileProgressStore persists upload progress as one JSON file per key inside dir, so
+// resumable uploads survive a process restart.
+type FileProgressStore struct {
+	dir string
+}
+
+// NewFileProgressStore returns a FileProgressStore that writes state files into dir. The
+// directory must already exist.
+func NewFileProgressStore(dir string) *FileProgressStore {
+	return &FileProgressStore{dir: dir}
+}
+
+// Save writes state for key to a JSON file in the store's directory.
+func (f *FileProgressStore) Save(key string, state UploadState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("could not marshal upload state for %s: %w", key, err)
+	}
+	if err := os.WriteFile(f.path(key), data, 0o600); err != nil {
+		return fmt.Errorf("could not write upload state for %s: %w", key, err)
+	}
+	return nil
+}
+
+// Load reads back the state previously saved for key, or returns ErrProgressNotFound if no
+// state file exists for it.
+func (f *FileProgressStore) Load(key string) (UploadState, error) {
+	data, err := os.ReadFile(f.path(key))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return UploadState{}, ErrProgressNotFound
+		}
+		return UploadState{}, fmt.Errorf("could not read upload state for %s: %w", key, err)
+	}
+	var state UploadState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return UploadState{}, fmt.Errorf("could not unmarshal upload state for %s: %w", key, err)
+	}
+	return state, nil
+}
+
+func (f *FileProgressStore) path(key string) string {
+	return filepath.Join(f.dir, url.QueryEscape(key)+".json")
+}
+
+// Upload is a handle onto a single, possibly resumable, multipart upload. Obtain one with
+// StartUpload; WriteFrom streams bytes to S3, persisting progress after every confirmed
+// part, and Abort or Complete finish the upload explicitly.
+type Upload struct {
+	UploadId string
+	Parts    []types.CompletedPart
+	Offset   int
+
+	ctx      context.Context
+	store    Store
+	resp     *s3.CreateMultipartUploadOutput
+	key      string
+	nextPart int
+	mu       sync.Mutex
+}
+
+// StartUpload begins a resumable upload for prefix+name in bucketname. If the Store's
+// ProgressStore already has state saved for this object, the upload resumes: the persisted
+// part list is cross-checked against S3's own ListParts (by ETag) and the handle continues
+// from the first part either store disagrees on or has no record of. Otherwise a brand new
+// multipart upload is created.
+func (s Store) StartUpload(ctx context.Context, prefix, bucketname, name string) (*Upload, error) {
+	key := prefix + name
+
+	if state, err := s.progressStore.Load(key); err == nil {
+		return s.resumeUpload(ctx, bucketname, key, state)
+	} else if !errors.Is(err, ErrProgressNotFound) {
+		return nil, fmt.Errorf("could not load upload progress for %s: %w", key, err)
+	}
+
+	resp, err := s.api.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+		Bucket: aws.String(bucketname),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not create multipart upload: %w.", err)
+	}
+
+	return &Upload{
+		UploadId: aws.ToString(resp.UploadId),
+		ctx:      ctx,
+		store:    s,
+		resp:     resp,
+		key:      key,
+		nextPart: 1,
+	}, nil
+}
+
+// resumeUpload reconstructs an Upload from persisted state, keeping only the prefix of
+// parts that S3's ListParts still agrees with byte for byte (matched by ETag).
+func (s Store) resumeUpload(ctx context.Context, bucketname, key string, state UploadState) (*Upload, error) {
+	listResp, err := s.api.ListParts(ctx, &s3.ListPartsInput{
+		Bucket:   aws.String(bucketname),
+		Key:      aws.String(key),
+		UploadId: aws.String(state.UploadId),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not list parts for upload %s: %w", state.UploadId, err)
+	}
+
+	remoteETags := make(map[int32]string, len(listResp.Parts))
+	for _, p := range listResp.Parts {
+		remoteETags[p.PartNumber] = aws.ToString(p.ETag)
+	}
+
+	// Parts are only confirmed while they form an unbroken sequence starting at 1: a part
+	// missing from state (e.g. a worker crashed before reporting it) must not let later,
+	// already-confirmed part numbers mask the gap, or the upload would resume past it and
+	// complete with a hole where that part belongs.
+	// Concurrent uploads persist parts in completion order, so walk them by part number.
+	persisted := append([]types.CompletedPart(nil), state.Parts...)
+	sort.Slice(persisted, func(i, j int) bool {
+		return persisted[i].PartNumber < persisted[j].PartNumber
+	})
+	expected := int32(1)
+	confirmed := make([]types.CompletedPart, 0, len(persisted))
+	for _, part := range persisted {
+		if part.PartNumber != expected || remoteETags[part.PartNumber] != aws.ToString(part.ETag) {
+			break
+		}
+		confirmed = append(confirmed, part)
+		expected++
+	}
+
+	return &Upload{
+		UploadId: state.UploadId,
+		Parts:    confirmed,
+		Offset:   sumPartSizes(listResp.Parts, confirmed),
+		ctx:      ctx,
+		store:    s,
+		resp: &s3.CreateMultipartUploadOutput{
+			Bucket:   aws.String(bucketname),
+			Key:      aws.String(key),
+			UploadId: aws.String(state.UploadId),
+		},
+		key:      key,
+		nextPart: int(expected),
+	}, nil
+}
+
+func sumPartSizes(remote []types.Part, confirmed []types.CompletedPart) int {
+	sizes := make(map[int32]int64, len(remote))
+	for _, p := range remote {
+		sizes[p.PartNumber] = p.Size
+	}
+	var total int
+	for _, p := range confirmed {
+		total += int(sizes[p.PartNumber])
+	}
+	return total
+}
+
+// WriteFrom streams r to S3 as one or more parts, continuing from the part number this
+// Upload left off at, and persists progress via the Store's ProgressStore after each part
+// S3 confirms. It returns the number of bytes read from r during this call. It does not
+// abort or complete the upload; call Abort or Complete explicitly once done.
+func (u *Upload) WriteFrom(r io.Reader) (int, error) {
+	onPart := func(part types.CompletedPart) error {
+		u.mu.Lock()
+		u.Parts = append(u.Parts, part)
+		state := UploadState{UploadId: u.UploadId, Parts: append([]types.CompletedPart(nil), u.Parts...)}
+		u.mu.Unlock()
+		return u.store.progressStore.Save(u.key, state)
+	}
+
+	var (
+		n   int
+		err error
+	)
+	if u.store.uploadConcurrency <= 1 {
+		n, err = u.store.uploadPartsSequential(u.ctx, u.resp, r, u.nextPart, onPart)
+	} else {
+		n, err = u.store.uploadPartsConcurrent(u.ctx, u.resp, r, u.nextPart, onPart)
+	}
+
+	u.mu.Lock()
+	u.Offset += n
+	u.nextPart = len(u.Parts) + 1
+	u.mu.Unlock()
+
+	return n, err
+}
+
+// Abort cancels the multipart upload, discarding every part already uploaded.
+func (u *Upload) Abort() error {
+	return u.store.abortMultipartUpload(u.ctx, u.resp)
+}
+
+// Complete finalizes the multipart upload with the parts confirmed so far.
+func (u *Upload) Complete() (*s3.CompleteMultipartUploadOutput, error) {
+	u.mu.Lock()
+	parts := append([]types.CompletedPart(nil), u.Parts...)
+	u.mu.Unlock()
+
+	sort.Slice(parts, func(i, j int) bool {
+		return parts[i].PartNumber < parts[j].PartNumber
+	})
+
+	return u.store.completeMultipartUpload(u.ctx, u.resp, parts)
+}
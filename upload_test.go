@@ -0,0 +1,87 @@
+package s3stream
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// TestResumeUpload_StopsAtFirstGap reproduces a crash that left part 3 unconfirmed while
+// parts 4 and 5 did get reported: resumeUpload must not let the later, still-matching part
+// numbers mask the gap at 3, or the upload would resume past it and complete with a hole.
+func TestResumeUpload_StopsAtFirstGap(t *testing.T) {
+	remoteParts := []types.Part{
+		{PartNumber: 1, ETag: aws.String("etag-1"), Size: 10},
+		{PartNumber: 2, ETag: aws.String("etag-2"), Size: 10},
+		{PartNumber: 4, ETag: aws.String("etag-4"), Size: 10},
+		{PartNumber: 5, ETag: aws.String("etag-5"), Size: 10},
+	}
+	s := newTestStore(&fakeAPI{
+		listPartsFn: func(context.Context, *s3.ListPartsInput) (*s3.ListPartsOutput, error) {
+			return &s3.ListPartsOutput{Parts: remoteParts}, nil
+		},
+	})
+
+	state := UploadState{
+		UploadId: "upload-id",
+		Parts: []types.CompletedPart{
+			{PartNumber: 1, ETag: aws.String("etag-1")},
+			{PartNumber: 2, ETag: aws.String("etag-2")},
+			{PartNumber: 4, ETag: aws.String("etag-4")},
+			{PartNumber: 5, ETag: aws.String("etag-5")},
+		},
+	}
+
+	upload, err := s.resumeUpload(context.Background(), "bucket", "key", state)
+	if err != nil {
+		t.Fatalf("resumeUpload() error = %v", err)
+	}
+	if upload.nextPart != 3 {
+		t.Fatalf("nextPart = %d, want 3", upload.nextPart)
+	}
+	if len(upload.Parts) != 2 {
+		t.Fatalf("confirmed parts = %d, want 2: %+v", len(upload.Parts), upload.Parts)
+	}
+	if upload.Offset != 20 {
+		t.Fatalf("Offset = %d, want 20 (parts 1 and 2 only)", upload.Offset)
+	}
+}
+
+// TestResumeUpload_NoGapConfirmsEverything is the non-gapped control case: every persisted
+// part matches S3 and forms an unbroken sequence, so resumeUpload should confirm all of it.
+func TestResumeUpload_NoGapConfirmsEverything(t *testing.T) {
+	remoteParts := []types.Part{
+		{PartNumber: 1, ETag: aws.String("etag-1"), Size: 10},
+		{PartNumber: 2, ETag: aws.String("etag-2"), Size: 10},
+	}
+	s := newTestStore(&fakeAPI{
+		listPartsFn: func(context.Context, *s3.ListPartsInput) (*s3.ListPartsOutput, error) {
+			return &s3.ListPartsOutput{Parts: remoteParts}, nil
+		},
+	})
+
+	state := UploadState{
+		UploadId: "upload-id",
+		Parts: []types.CompletedPart{
+			{PartNumber: 1, ETag: aws.String("etag-1")},
+			{PartNumber: 2, ETag: aws.String("etag-2")},
+		},
+	}
+
+	upload, err := s.resumeUpload(context.Background(), "bucket", "key", state)
+	if err != nil {
+		t.Fatalf("resumeUpload() error = %v", err)
+	}
+	if upload.nextPart != 3 {
+		t.Fatalf("nextPart = %d, want 3", upload.nextPart)
+	}
+	if len(upload.Parts) != 2 {
+		t.Fatalf("confirmed parts = %d, want 2: %+v", len(upload.Parts), upload.Parts)
+	}
+	if upload.Offset != 20 {
+		t.Fatalf("Offset = %d, want 20", upload.Offset)
+	}
+}